@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	networkingv1 "k8s.io/api/networking/v1"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+// conflictMode and inputFile back the print command's flags; conflictMode is
+// validated against common.ConflictMode's known values in newConvertCommand's
+// RunE.
+var (
+	conflictMode string
+	inputFile    string
+)
+
+func newConvertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Convert Ingress resources to Gateway API resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode := common.ConflictMode(conflictMode)
+			switch mode {
+			case common.ConflictModeWarn, common.ConflictModeError, common.ConflictModeResolve:
+			default:
+				return fmt.Errorf("invalid --conflict-mode %q: must be one of warn, error, resolve", conflictMode)
+			}
+
+			ingresses, err := readIngresses(inputFile)
+			if err != nil {
+				return err
+			}
+
+			groups, report, err := common.GroupIngressPathsWithConflicts(ingresses, common.DefaultPathTranslator{}, mode)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range report.Conflicts {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", c.Description)
+			}
+
+			return printMatchGroups(cmd.OutOrStdout(), groups)
+		},
+	}
+
+	cmd.Flags().StringVar(&conflictMode, "conflict-mode", string(common.ConflictModeWarn),
+		"How to handle host/path routing conflicts across grouped Ingresses: warn, error, or resolve (reorder rules by specificity)")
+	cmd.Flags().StringVar(&inputFile, "input-file", "",
+		"Path to a YAML or JSON file containing one or more Ingress resources to convert")
+	cmd.MarkFlagRequired("input-file")
+
+	return cmd
+}
+
+// readIngresses decodes every Ingress document in the YAML or JSON file at
+// path, skipping documents of any other Kind so a file that mixes Ingresses
+// with, say, Services can still be pointed at directly.
+func readIngresses(path string) ([]networkingv1.Ingress, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var ingresses []networkingv1.Ingress
+	decoder := kyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var ing networkingv1.Ingress
+		if err := decoder.Decode(&ing); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if ing.Kind != "" && ing.Kind != "Ingress" {
+			continue
+		}
+		ingresses = append(ingresses, ing)
+	}
+
+	return ingresses, nil
+}
+
+// printMatchGroups renders the HTTPRouteRule each MatchGroup resolves to, one
+// YAML document per group, in the order GroupIngressPathsWithConflicts
+// returned them -- reordered by specificity already, when mode is "resolve".
+func printMatchGroups(w io.Writer, groups []common.MatchGroup) error {
+	for _, g := range groups {
+		rule := gatewayv1.HTTPRouteRule{}
+		for _, p := range g.Paths {
+			match := p.Match
+			rule.Matches = append(rule.Matches, gatewayv1.HTTPRouteMatch{Path: &match})
+			rule.Filters = append(rule.Filters, p.Filters...)
+		}
+
+		out, err := yaml.Marshal(rule)
+		if err != nil {
+			return fmt.Errorf("marshaling match group %q: %w", g.Key, err)
+		}
+		fmt.Fprintf(w, "---\n# match group %q\n%s", g.Key, out)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ats
+
+import (
+	"strconv"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+// ConversionResult is everything Convert produces for a single Ingress:
+// the HTTPRouteFilters derived from annotations with a direct Gateway API
+// equivalent, a BackendTrafficPolicy for settings that have none, and any
+// warnings about annotations that could not be converted at all.
+type ConversionResult struct {
+	Filters       []gatewayv1.HTTPRouteFilter
+	TrafficPolicy *BackendTrafficPolicy
+	Notifications field.ErrorList
+}
+
+// Convert translates the ATS-specific annotations of ing into Gateway API
+// filters and, where no direct mapping exists, a BackendTrafficPolicy. The
+// server-snippet annotation has no Gateway API or BackendTrafficPolicy
+// equivalent (it is raw ATS Lua), so it is surfaced as a Notification
+// instead of being silently dropped.
+func Convert(ing networkingv1.Ingress, backendName string) ConversionResult {
+	result := ConversionResult{}
+	ann := ing.Annotations
+
+	if target, ok := ann[rewriteTargetAnnotation]; ok {
+		result.Filters = append(result.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:            gatewayv1.FullPathHTTPPathModifier,
+					ReplaceFullPath: common.PtrTo(target),
+				},
+			},
+		})
+	}
+
+	if to, ok := ann[redirectToAnnotation]; ok {
+		result.Filters = append(result.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Hostname: common.PtrTo(gatewayv1.PreciseHostname(to)),
+			},
+		})
+	}
+
+	if header, ok := ann[upstreamHeaderAnnotation]; ok {
+		if name, value, ok := splitHeaderAnnotation(header); ok {
+			result.Filters = append(result.Filters, gatewayv1.HTTPRouteFilter{
+				Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+					Add: []gatewayv1.HTTPHeader{{
+						Name:  gatewayv1.HTTPHeaderName(name),
+						Value: value,
+					}},
+				},
+			})
+		} else {
+			result.Notifications = append(result.Notifications, field.Invalid(
+				field.NewPath("metadata", "annotations", upstreamHeaderAnnotation), header,
+				"expected a \"Header-Name: value\" pair"))
+		}
+	}
+
+	if timeout, ok := ann[connectTimeoutAnnotation]; ok {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil {
+			result.Notifications = append(result.Notifications, field.Invalid(
+				field.NewPath("metadata", "annotations", connectTimeoutAnnotation), timeout, "expected an integer number of seconds"))
+		} else {
+			result.TrafficPolicy = &BackendTrafficPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: ing.Name + "-backend-traffic", Namespace: ing.Namespace},
+				Spec: BackendTrafficPolicySpec{
+					TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReference{{
+						Group: "",
+						Kind:  "Service",
+						Name:  gatewayv1.ObjectName(backendName),
+					}},
+					ConnectTimeoutSeconds: common.PtrTo(int32(seconds)),
+				},
+			}
+		}
+	}
+
+	if _, ok := ann[serverSnippetAnnotation]; ok {
+		result.Notifications = append(result.Notifications, field.Invalid(
+			field.NewPath("metadata", "annotations", serverSnippetAnnotation), "",
+			"raw ATS Lua server snippets have no Gateway API equivalent and were not converted"))
+	}
+
+	return result
+}
+
+// splitHeaderAnnotation parses ATS' "Header-Name: value" upstream header
+// annotation format.
+func splitHeaderAnnotation(s string) (name, value string, ok bool) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	name = strings.TrimSpace(parts[0])
+	value = strings.TrimSpace(parts[1])
+	return name, value, name != "" && value != ""
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestConvertRewriteTarget(t *testing.T) {
+	ing := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{rewriteTargetAnnotation: "/new"}},
+	}
+
+	result := Convert(ing, "backend-svc")
+
+	require.Len(t, result.Filters, 1)
+	require.Equal(t, gatewayv1.HTTPRouteFilterURLRewrite, result.Filters[0].Type)
+	require.Equal(t, "/new", *result.Filters[0].URLRewrite.Path.ReplaceFullPath)
+}
+
+func TestConvertConnectTimeoutProducesTrafficPolicy(t *testing.T) {
+	ing := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: map[string]string{connectTimeoutAnnotation: "5"}},
+	}
+
+	result := Convert(ing, "backend-svc")
+
+	require.NotNil(t, result.TrafficPolicy)
+	require.Equal(t, int32(5), *result.TrafficPolicy.Spec.ConnectTimeoutSeconds)
+}
+
+func TestConvertServerSnippetSurfacesNotification(t *testing.T) {
+	ing := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{serverSnippetAnnotation: "lua code"}},
+	}
+
+	result := Convert(ing, "backend-svc")
+
+	require.Len(t, result.Notifications, 1)
+}
+
+func TestConvertInvalidUpstreamHeaderSurfacesNotification(t *testing.T) {
+	ing := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{upstreamHeaderAnnotation: "not-a-header-pair"}},
+	}
+
+	result := Convert(ing, "backend-svc")
+
+	require.Empty(t, result.Filters)
+	require.Len(t, result.Notifications, 1)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ats
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// BackendTrafficPolicy is this provider's CR for ATS settings that have no
+// direct Gateway API equivalent, such as a per-backend connect timeout. It
+// deliberately mirrors the shape of the policy-attachment CRDs shipped by
+// Gateway API implementations (an object reference plus a typed Spec) so
+// that whichever implementation the user targets can adapt it mechanically.
+type BackendTrafficPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackendTrafficPolicySpec `json:"spec"`
+}
+
+// BackendTrafficPolicySpec holds the ATS settings this provider could not
+// express as a standard HTTPRouteFilter.
+type BackendTrafficPolicySpec struct {
+	TargetRefs []gatewayv1alpha2.LocalPolicyTargetReference `json:"targetRefs"`
+
+	// ConnectTimeoutSeconds mirrors
+	// ats.ingress.kubernetes.io/connect-timeout-seconds.
+	ConnectTimeoutSeconds *int32 `json:"connectTimeoutSeconds,omitempty"`
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ats converts Apache Traffic Server ingress controller annotations
+// into Gateway API HTTPRouteFilters, falling back to a provider-specific
+// BackendTrafficPolicy for annotations with no direct Gateway API
+// equivalent.
+package ats
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+const (
+	regexPathAnnotation      = "ats.ingress.kubernetes.io/regex-path"
+	rewriteTargetAnnotation  = "ats.ingress.kubernetes.io/rewrite-target"
+	redirectToAnnotation     = "ats.ingress.kubernetes.io/redirect-to"
+	upstreamHeaderAnnotation = "ats.ingress.kubernetes.io/upstream-header-add"
+	serverSnippetAnnotation  = "ats.ingress.kubernetes.io/server-snippet"
+	connectTimeoutAnnotation = "ats.ingress.kubernetes.io/connect-timeout-seconds"
+)
+
+// Translator is the ATS common.PathTranslator. It keys regex-annotated
+// paths (ats.ingress.kubernetes.io/regex-path) as
+// PathMatchRegularExpression so they group distinctly from literal paths
+// that happen to share the same raw string.
+type Translator struct{}
+
+// Translate implements common.PathTranslator.
+func (Translator) Translate(path networkingv1.HTTPIngressPath, annotations map[string]string) common.PathSemantics {
+	if annotations[regexPathAnnotation] == "true" {
+		return common.PathSemantics{
+			Match: gatewayv1.HTTPPathMatch{
+				Type:  common.PtrTo(gatewayv1.PathMatchRegularExpression),
+				Value: common.PtrTo(path.Path),
+			},
+			GroupKeySuffix: "regex",
+		}
+	}
+	return common.PathSemantics{Match: defaultMatch(path)}
+}
+
+// defaultMatch falls back to PathMatchPathPrefix for PathTypeImplementationSpecific
+// (and an unset PathType), since Gateway API has no "implementation specific"
+// match type.
+func defaultMatch(path networkingv1.HTTPIngressPath) gatewayv1.HTTPPathMatch {
+	matchType := gatewayv1.PathMatchPathPrefix
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		case networkingv1.PathTypePrefix:
+			matchType = gatewayv1.PathMatchPathPrefix
+		}
+	}
+	return gatewayv1.HTTPPathMatch{
+		Type:  common.PtrTo(matchType),
+		Value: common.PtrTo(path.Path),
+	}
+}
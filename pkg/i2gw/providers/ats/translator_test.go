@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+func TestTranslateRegexPath(t *testing.T) {
+	path := networkingv1.HTTPIngressPath{Path: "/api/.*", PathType: common.PtrTo(networkingv1.PathTypePrefix)}
+
+	sem := Translator{}.Translate(path, map[string]string{regexPathAnnotation: "true"})
+
+	require.Equal(t, gatewayv1.PathMatchRegularExpression, *sem.Match.Type)
+	require.Equal(t, "regex", sem.GroupKeySuffix)
+}
+
+func TestTranslateLiteralPathHasNoSuffix(t *testing.T) {
+	path := networkingv1.HTTPIngressPath{Path: "/api", PathType: common.PtrTo(networkingv1.PathTypePrefix)}
+
+	sem := Translator{}.Translate(path, nil)
+
+	require.Equal(t, gatewayv1.PathMatchPathPrefix, *sem.Match.Type)
+	require.Empty(t, sem.GroupKeySuffix)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Annotation keys of the nginx.ingress.kubernetes.io/canary* family. They are
+// declared here, rather than in the ingress-nginx provider package, so that
+// any provider whose annotations follow the same canary convention (several
+// nginx-compatible controllers do) can reuse the parsing and merge logic.
+const (
+	CanaryAnnotation            = "nginx.ingress.kubernetes.io/canary"
+	CanaryWeightAnnotation      = "nginx.ingress.kubernetes.io/canary-weight"
+	CanaryWeightTotalAnnotation = "nginx.ingress.kubernetes.io/canary-weight-total"
+	CanaryByHeaderAnnotation    = "nginx.ingress.kubernetes.io/canary-by-header"
+	CanaryByHeaderValAnnotation = "nginx.ingress.kubernetes.io/canary-by-header-value"
+	CanaryByCookieAnnotation    = "nginx.ingress.kubernetes.io/canary-by-cookie"
+
+	defaultCanaryWeightTotal = 100
+)
+
+// canaryStrategy identifies which canary mechanism a canary Ingress used to
+// select traffic for its backend.
+type canaryStrategy string
+
+const (
+	canaryStrategyWeight canaryStrategy = "weight"
+	canaryStrategyHeader canaryStrategy = "header"
+	canaryStrategyCookie canaryStrategy = "cookie"
+)
+
+// canaryMatch records how a pathsByMatchGroupType should be emitted once one
+// of its paths came from an Ingress carrying canary annotations: weighted
+// groups collapse into a single HTTPRoute rule with two weighted backendRefs,
+// while header/cookie groups are kept as their own, separately matched rule
+// so they can be ordered ahead of the primary rule.
+type canaryMatch struct {
+	strategy canaryStrategy
+
+	// primaryPathIdx/canaryPathIdx index into the owning group's paths slice.
+	primaryPathIdx int
+	canaryPathIdx  int
+
+	// weight and weightTotal are only meaningful for canaryStrategyWeight;
+	// the primary backend receives weightTotal-weight.
+	weight      int32
+	weightTotal int32
+
+	// headerName/headerValue and cookieName carry the match condition for
+	// canaryStrategyHeader/canaryStrategyCookie groups respectively.
+	headerName  string
+	headerValue string
+	cookieName  string
+}
+
+// canaryAnnotations is the parsed canary annotation set of a single Ingress.
+type canaryAnnotations struct {
+	isCanary    bool
+	hasWeight   bool
+	weight      int32
+	weightTotal int32
+	byHeader    string
+	byHeaderVal string
+	byCookie    string
+}
+
+// parseCanaryAnnotations extracts the nginx canary annotation family from an
+// Ingress' annotations. When the canary annotation is absent or not "true"
+// it returns a canaryAnnotations with isCanary false and only weightTotal
+// set (to its default of 100).
+func parseCanaryAnnotations(annotations map[string]string) canaryAnnotations {
+	ca := canaryAnnotations{weightTotal: defaultCanaryWeightTotal}
+	if annotations[CanaryAnnotation] != "true" {
+		return ca
+	}
+	ca.isCanary = true
+
+	if w, ok := annotations[CanaryWeightAnnotation]; ok {
+		if parsed, err := strconv.ParseInt(w, 10, 32); err == nil {
+			ca.weight = int32(parsed)
+			ca.hasWeight = true
+		}
+	}
+	if wt, ok := annotations[CanaryWeightTotalAnnotation]; ok {
+		if parsed, err := strconv.ParseInt(wt, 10, 32); err == nil {
+			ca.weightTotal = int32(parsed)
+		}
+	}
+	ca.byHeader = annotations[CanaryByHeaderAnnotation]
+	ca.byHeaderVal = annotations[CanaryByHeaderValAnnotation]
+	ca.byCookie = annotations[CanaryByCookieAnnotation]
+
+	return ca
+}
+
+// strategy returns which canary mechanism the annotation set requests,
+// preferring header/cookie matching over weight when more than one is set,
+// since header/cookie based canaries are unconditional selectors.
+func (ca canaryAnnotations) strategy() canaryStrategy {
+	switch {
+	case ca.byHeader != "":
+		return canaryStrategyHeader
+	case ca.byCookie != "":
+		return canaryStrategyCookie
+	default:
+		return canaryStrategyWeight
+	}
+}
+
+// mixedStrategies reports whether more than one canary selection mechanism
+// was set on the same Ingress, which nginx itself does not define an
+// ordering for.
+func (ca canaryAnnotations) mixedStrategies() bool {
+	set := 0
+	if ca.byHeader != "" {
+		set++
+	}
+	if ca.byCookie != "" {
+		set++
+	}
+	if ca.hasWeight {
+		set++
+	}
+	return set > 1
+}
+
+// validWeight reports whether the parsed canary-weight falls within nginx's
+// documented 0-weightTotal range (weightTotal defaults to 100, but
+// canary-weight-total can change it). A weight outside that range would,
+// once subtracted from weightTotal, produce a negative BackendRef.Weight,
+// which violates Gateway API's own validation on that field.
+func (ca canaryAnnotations) validWeight() bool {
+	return ca.weight >= 0 && ca.weight <= ca.weightTotal
+}
+
+// annotateCanaryGroups inspects the rules backing each group produced by
+// groupPaths and, whenever exactly one canary-annotated path shares a key
+// with exactly one non-canary (primary) path, tags the group with the
+// resolved canaryMatch so downstream converters can emit a single weighted
+// (or header/cookie matched) HTTPRoute rule instead of two duplicate ones.
+// Groups with more than one primary or more than one canary path are left
+// untagged (group.canary stays nil) since canary merging only has a defined
+// meaning for a single primary/canary pair; the converter's plain,
+// multi-backend rule already handles every path in that case without
+// dropping any of them.
+//
+// Validation warnings discovered along the way, such as a canary Ingress
+// mixing weight and header/cookie selection within the same match group or a
+// canary-weight outside the 0-weightTotal range, are recorded on the group's
+// validationErrs so callers can surface them through the shared
+// field.ErrorList mechanism.
+func annotateCanaryGroups(groups []pathsByMatchGroupType, rules []ingressRule) []pathsByMatchGroupType {
+	for i := range groups {
+		group := &groups[i]
+
+		var primaryIdx, canaryIdx = -1, -1
+		var canaryAnn canaryAnnotations
+		primaryCount, canaryCount := 0, 0
+		weightOutOfRange := false
+
+		for pathIdx, p := range group.paths {
+			ann := parseCanaryAnnotations(rules[p.ruleIdx].annotations)
+			if !ann.isCanary {
+				primaryCount++
+				if primaryIdx == -1 {
+					primaryIdx = pathIdx
+				}
+				continue
+			}
+			canaryCount++
+			if ann.mixedStrategies() {
+				group.validationErrs = append(group.validationErrs, field.Invalid(field.NewPath("metadata", "annotations"),
+					CanaryByHeaderAnnotation, "canary Ingress sets more than one of weight, header and cookie based selection; only one strategy is applied"))
+			}
+			if ann.strategy() == canaryStrategyWeight && ann.hasWeight && !ann.validWeight() {
+				weightOutOfRange = true
+				group.validationErrs = append(group.validationErrs, field.Invalid(field.NewPath("metadata", "annotations"),
+					CanaryWeightAnnotation, fmt.Sprintf("canary-weight %d is outside the valid 0-%d range; falling back to a plain multi-backend rule for this match group", ann.weight, ann.weightTotal)))
+			}
+			canaryIdx = pathIdx
+			canaryAnn = ann
+		}
+
+		if primaryIdx == -1 || canaryIdx == -1 {
+			continue
+		}
+
+		if primaryCount > 1 || canaryCount > 1 {
+			group.validationErrs = append(group.validationErrs, field.Invalid(field.NewPath("spec", "rules"),
+				group.key, fmt.Sprintf("match group has %d primary and %d canary path(s); canary merging requires exactly one of each, falling back to a plain multi-backend rule", primaryCount, canaryCount)))
+			continue
+		}
+
+		if weightOutOfRange {
+			continue
+		}
+
+		group.canary = &canaryMatch{
+			primaryPathIdx: primaryIdx,
+			canaryPathIdx:  canaryIdx,
+			strategy:       canaryAnn.strategy(),
+			weight:         canaryAnn.weight,
+			weightTotal:    canaryAnn.weightTotal,
+			headerName:     canaryAnn.byHeader,
+			headerValue:    canaryAnn.byHeaderVal,
+			cookieName:     canaryAnn.byCookie,
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// conflictEntry is a group's per-DetectAndResolveConflicts-pass summary:
+// the hosts it applies to and how specific its path match is.
+type conflictEntry struct {
+	idx       int
+	hosts     []string
+	path      string
+	specifity int
+}
+
+// ConflictMode controls how DetectAndResolveConflicts (and the
+// GroupIngressPathsWithConflicts entry point built on it) reacts to a
+// cross-host/cross-namespace conflict: "warn" reports it but leaves rule
+// order untouched, "error" fails the conversion, and "resolve" reorders
+// rules so more-specific matches precede less-specific ones, preserving
+// Ingress "longest match wins" behavior under Gateway API's rule-ordering
+// semantics.
+type ConflictMode string
+
+const (
+	ConflictModeWarn    ConflictMode = "warn"
+	ConflictModeError   ConflictMode = "error"
+	ConflictModeResolve ConflictMode = "resolve"
+)
+
+// PathConflict describes two groups whose matches overlap in a way that
+// Ingress and Gateway API would resolve differently: Ingress always prefers
+// the longest/most-specific path match regardless of declaration order,
+// while Gateway API evaluates HTTPRoute rules in the order they are
+// declared.
+type PathConflict struct {
+	// MoreSpecificKey/LessSpecificKey are the pathsByMatchGroupType keys
+	// of the overlapping groups, more-specific first.
+	MoreSpecificKey string
+	LessSpecificKey string
+	Hosts           []string
+	Description     string
+}
+
+// ConflictReport is the result of a DetectAndResolveConflicts pass: every
+// conflict found. The reordered groups of ConflictModeResolve are not part
+// of it; DetectAndResolveConflicts returns them separately.
+type ConflictReport struct {
+	Conflicts []PathConflict
+}
+
+// ConflictError is returned by DetectAndResolveConflicts in ConflictModeError
+// when at least one conflict was found.
+type ConflictError struct {
+	Report ConflictReport
+}
+
+func (e *ConflictError) Error() string {
+	msgs := make([]string, 0, len(e.Report.Conflicts))
+	for _, c := range e.Report.Conflicts {
+		msgs = append(msgs, c.Description)
+	}
+	return fmt.Sprintf("%d routing conflict(s) detected: %s", len(e.Report.Conflicts), strings.Join(msgs, "; "))
+}
+
+// DetectAndResolveConflicts inspects the groups produced by groupPaths for
+// overlaps across DISTINCT groups that groupPaths' per-host grouping cannot
+// see on its own: matches that apply to overlapping (including wildcard)
+// host sets, where one group's PathPrefix/Exact/ImplementationSpecific match
+// is a more specific subset of another's. It does not need to check for a
+// single group spanning more than one host, since groupPathsWithTranslator
+// folds each rule's Host into its grouping key -- a group's paths always
+// share exactly one host by construction. Depending on mode it returns the
+// groups unchanged (ConflictModeWarn), an error (ConflictModeError), or the
+// groups reordered so more-specific matches precede less-specific ones
+// (ConflictModeResolve), alongside a ConflictReport describing what it
+// found.
+func DetectAndResolveConflicts(groups []pathsByMatchGroupType, rules []ingressRule, mode ConflictMode) ([]pathsByMatchGroupType, ConflictReport, error) {
+	report := ConflictReport{}
+
+	entries := make([]conflictEntry, 0, len(groups))
+	for i, g := range groups {
+		entries = append(entries, conflictEntry{
+			idx:       i,
+			hosts:     groupHosts(g, rules),
+			path:      groupPath(g),
+			specifity: len(groupPath(g)),
+		})
+	}
+
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			if !hostsOverlap(entries[i].hosts, entries[j].hosts) {
+				continue
+			}
+			if !pathsOverlap(entries[i].path, entries[j].path) {
+				continue
+			}
+
+			moreSpecific, lessSpecific := i, j
+			if entries[j].specifity > entries[i].specifity {
+				moreSpecific, lessSpecific = j, i
+			}
+
+			report.Conflicts = append(report.Conflicts, PathConflict{
+				MoreSpecificKey: groups[entries[moreSpecific].idx].key,
+				LessSpecificKey: groups[entries[lessSpecific].idx].key,
+				Hosts:           intersectHosts(entries[i].hosts, entries[j].hosts),
+				Description: fmt.Sprintf("path %q overlaps %q for host(s) %v; Ingress would prefer the longer match but HTTPRoute evaluates rules in order",
+					groups[entries[moreSpecific].idx].key, groups[entries[lessSpecific].idx].key, intersectHosts(entries[i].hosts, entries[j].hosts)),
+			})
+		}
+	}
+
+	if len(report.Conflicts) == 0 {
+		return groups, report, nil
+	}
+
+	switch mode {
+	case ConflictModeError:
+		return groups, report, &ConflictError{Report: report}
+	case ConflictModeResolve:
+		return reorderBySpecificity(groups, entries), report, nil
+	default: // ConflictModeWarn
+		return groups, report, nil
+	}
+}
+
+func groupHosts(g pathsByMatchGroupType, rules []ingressRule) []string {
+	hosts := map[string]bool{}
+	for _, p := range g.paths {
+		hosts[rules[p.ruleIdx].Host] = true
+	}
+	result := make([]string, 0, len(hosts))
+	for h := range hosts {
+		result = append(result, h)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func groupPath(g pathsByMatchGroupType) string {
+	if len(g.paths) == 0 {
+		return ""
+	}
+	return g.paths[0].path.Path
+}
+
+// hostsOverlap reports whether two host sets could match the same request,
+// expanding "*.example.com" wildcards to cover "foo.example.com".
+func hostsOverlap(a, b []string) bool {
+	for _, ha := range a {
+		for _, hb := range b {
+			if hostMatches(ha, hb) || hostMatches(hb, ha) || ha == hb {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intersectHosts(a, b []string) []string {
+	var out []string
+	for _, ha := range a {
+		for _, hb := range b {
+			if ha == hb || hostMatches(ha, hb) || hostMatches(hb, ha) {
+				out = append(out, ha)
+			}
+		}
+	}
+	return out
+}
+
+// hostMatches reports whether the wildcard host pattern (e.g.
+// "*.example.com") matches the candidate host.
+func hostMatches(pattern, candidate string) bool {
+	if !strings.HasPrefix(pattern, "*.") {
+		return false
+	}
+	suffix := pattern[1:] // ".example.com"
+	return strings.HasSuffix(candidate, suffix) && candidate != suffix[1:]
+}
+
+// pathsOverlap reports whether two path strings could both match the same
+// request path under Ingress "longest prefix wins" semantics: equality, or
+// one being a prefix of the other.
+func pathsOverlap(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// reorderBySpecificity returns groups reordered so that, among groups whose
+// paths overlap, the most specific (longest path) comes first -- the order
+// Gateway API needs to reproduce Ingress' "longest match wins" behavior.
+func reorderBySpecificity(groups []pathsByMatchGroupType, entries []conflictEntry) []pathsByMatchGroupType {
+	ordered := make([]conflictEntry, len(entries))
+	copy(ordered, entries)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].specifity > ordered[j].specifity
+	})
+
+	result := make([]pathsByMatchGroupType, 0, len(groups))
+	for _, e := range ordered {
+		result = append(result, groups[e.idx])
+	}
+	return result
+}
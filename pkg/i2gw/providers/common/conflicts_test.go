@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func ingressRuleForHostAndPath(host, path string, pathType networkingv1.PathType, svc string) ingressRule {
+	return ingressRule{
+		IngressRule: networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     path,
+						PathType: PtrTo(pathType),
+						Backend: networkingv1.IngressBackend{
+							Service: &networkingv1.IngressServiceBackend{Name: svc, Port: networkingv1.ServiceBackendPort{Number: 80}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestDetectAndResolveConflicts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         []ingressRule
+		mode          ConflictMode
+		wantConflicts int
+		wantErr       bool
+		wantFirstKey  string // groups[0].key after resolution, when wantErr is false
+	}{
+		{
+			name: "no rules, no conflicts",
+			rules: []ingressRule{},
+			mode:  ConflictModeWarn,
+		},
+		{
+			name: "disjoint hosts, no conflicts",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("a.example.com", "/api", networkingv1.PathTypePrefix, "a-svc"),
+				ingressRuleForHostAndPath("b.example.com", "/api/v1", networkingv1.PathTypePrefix, "b-svc"),
+			},
+			mode: ConflictModeWarn,
+		},
+		{
+			name: "overlapping prefix on same host, warn leaves order untouched",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("example.com", "/api", networkingv1.PathTypePrefix, "api-svc"),
+				ingressRuleForHostAndPath("example.com", "/api/v1", networkingv1.PathTypePrefix, "v1-svc"),
+			},
+			mode:          ConflictModeWarn,
+			wantConflicts: 1,
+			wantFirstKey:  "Prefix//api",
+		},
+		{
+			name: "overlapping prefix on same host, resolve reorders by specificity",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("example.com", "/api", networkingv1.PathTypePrefix, "api-svc"),
+				ingressRuleForHostAndPath("example.com", "/api/v1", networkingv1.PathTypePrefix, "v1-svc"),
+			},
+			mode:          ConflictModeResolve,
+			wantConflicts: 1,
+			wantFirstKey:  "Prefix//api/v1",
+		},
+		{
+			name: "overlapping prefix on same host, error mode fails",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("example.com", "/api", networkingv1.PathTypePrefix, "api-svc"),
+				ingressRuleForHostAndPath("example.com", "/api/v1", networkingv1.PathTypePrefix, "v1-svc"),
+			},
+			mode:          ConflictModeError,
+			wantConflicts: 1,
+			wantErr:       true,
+		},
+		{
+			name: "wildcard host overlaps concrete subdomain",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("*.example.com", "/api", networkingv1.PathTypePrefix, "wild-svc"),
+				ingressRuleForHostAndPath("foo.example.com", "/api/v1", networkingv1.PathTypePrefix, "foo-svc"),
+			},
+			mode:          ConflictModeWarn,
+			wantConflicts: 1,
+		},
+		{
+			name: "same path on disjoint hosts is not a conflict",
+			rules: []ingressRule{
+				ingressRuleForHostAndPath("a.example.com", "/", networkingv1.PathTypePrefix, "a-svc"),
+				ingressRuleForHostAndPath("b.example.com", "/", networkingv1.PathTypePrefix, "b-svc"),
+			},
+			mode: ConflictModeWarn,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			groups := groupPaths(tc.rules)
+			resolved, report, err := DetectAndResolveConflicts(groups, tc.rules, tc.mode)
+
+			require.Len(t, report.Conflicts, tc.wantConflicts)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantFirstKey != "" {
+				require.Equal(t, tc.wantFirstKey, resolved[0].key)
+			}
+		})
+	}
+}
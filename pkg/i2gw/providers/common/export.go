@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// MatchGroup is the exported view of a pathsByMatchGroupType: every
+// HTTPIngressPath, across the given Ingresses, whose PathType/Path resolve
+// to the same Gateway API match on the same rule host. Provider converters
+// collapse each
+// MatchGroup into a single HTTPRouteRule.
+type MatchGroup struct {
+	Key    string
+	Paths  []MatchGroupPath
+	Canary *CanaryMatch
+}
+
+// MatchGroupPath is a single path within a MatchGroup, annotated with
+// enough provenance (which Ingress, which rule, which path) for a converter
+// to recover the owning Ingress and build a BackendRef from it.
+type MatchGroupPath struct {
+	IngressIdx int
+	RuleIdx    int
+	PathIdx    int
+	Path       networkingv1.HTTPIngressPath
+
+	// Match and Filters are the PathTranslator's resolved Gateway API
+	// match and filters for this path. When GroupIngressPaths was called
+	// with a nil translator they come from DefaultPathTranslator, so Match
+	// is still set and Filters is nil.
+	Match   gatewayv1.HTTPPathMatch
+	Filters []gatewayv1.HTTPRouteFilter
+}
+
+// CanaryMatch is the exported view of canaryMatch: the resolved nginx
+// canary merge state for a MatchGroup.
+type CanaryMatch struct {
+	// Strategy is one of "weight", "header" or "cookie".
+	Strategy string
+
+	PrimaryPathIdx int
+	CanaryPathIdx  int
+
+	// Weight and WeightTotal are only meaningful when Strategy == "weight".
+	Weight      int32
+	WeightTotal int32
+
+	// HeaderName/HeaderValue and CookieName carry the match condition for
+	// the "header"/"cookie" strategies respectively.
+	HeaderName  string
+	HeaderValue string
+	CookieName  string
+}
+
+// flattenIngresses turns the given Ingresses' rules into ingressRules, each
+// tagged with the index of its owning Ingress (so canary annotations and
+// MatchGroupPath.IngressIdx can be resolved back to it).
+func flattenIngresses(ingresses []networkingv1.Ingress) (rules []ingressRule, owningIngressIdx []int) {
+	rules = make([]ingressRule, 0)
+	owningIngressIdx = make([]int, 0)
+	for i, ing := range ingresses {
+		for _, r := range ing.Spec.Rules {
+			rules = append(rules, ingressRule{IngressRule: r, annotations: ing.Annotations})
+			owningIngressIdx = append(owningIngressIdx, i)
+		}
+	}
+	return rules, owningIngressIdx
+}
+
+// toMatchGroups converts the internal pathsByMatchGroupType groups into the
+// exported MatchGroup view, resolving each path's Gateway API match and
+// filters through translator (falling back to DefaultPathTranslator when
+// nil, matching groupPathsWithTranslator's own fallback).
+func toMatchGroups(groups []pathsByMatchGroupType, rules []ingressRule, owningIngressIdx []int, translator PathTranslator) []MatchGroup {
+	if translator == nil {
+		translator = DefaultPathTranslator{}
+	}
+
+	result := make([]MatchGroup, 0, len(groups))
+	for _, g := range groups {
+		mg := MatchGroup{Key: g.key}
+		for _, p := range g.paths {
+			sem := translator.Translate(p.path, rules[p.ruleIdx].annotations)
+			mg.Paths = append(mg.Paths, MatchGroupPath{
+				IngressIdx: owningIngressIdx[p.ruleIdx],
+				RuleIdx:    p.ruleIdx,
+				PathIdx:    p.pathIdx,
+				Path:       p.path,
+				Match:      sem.Match,
+				Filters:    sem.Filters,
+			})
+		}
+		if g.canary != nil {
+			mg.Canary = &CanaryMatch{
+				Strategy:       string(g.canary.strategy),
+				PrimaryPathIdx: g.canary.primaryPathIdx,
+				CanaryPathIdx:  g.canary.canaryPathIdx,
+				Weight:         g.canary.weight,
+				WeightTotal:    g.canary.weightTotal,
+				HeaderName:     g.canary.headerName,
+				HeaderValue:    g.canary.headerValue,
+				CookieName:     g.canary.cookieName,
+			}
+		}
+		result = append(result, mg)
+	}
+	return result
+}
+
+// GroupIngressPathsWithConflicts is GroupIngressPaths followed by
+// DetectAndResolveConflicts: it groups the given Ingresses' paths and then
+// checks the result for cross-host/cross-namespace conflicts groupPaths'
+// single-key grouping cannot see on its own, resolving or reporting them per
+// mode. It is the entry point the CLI's --conflict-mode flag drives.
+func GroupIngressPathsWithConflicts(ingresses []networkingv1.Ingress, translator PathTranslator, mode ConflictMode) ([]MatchGroup, ConflictReport, error) {
+	rules, owningIngressIdx := flattenIngresses(ingresses)
+
+	groups := groupPathsWithTranslator(rules, translator)
+	groups, report, err := DetectAndResolveConflicts(groups, rules, mode)
+
+	return toMatchGroups(groups, rules, owningIngressIdx, translator), report, err
+}
+
+// GroupIngressPaths flattens the rules of the given Ingresses, keeping
+// track of each rule's owning Ingress so canary annotations can be
+// resolved, and groups their paths the same way groupPaths does. It is the
+// entry point provider packages use to reach the shared path-grouping (and
+// canary-merging) logic without depending on this package's unexported
+// types. A nil translator falls back to the default PathType/Path grouping.
+func GroupIngressPaths(ingresses []networkingv1.Ingress, translator PathTranslator) []MatchGroup {
+	rules, owningIngressIdx := flattenIngresses(ingresses)
+	groups := groupPathsWithTranslator(rules, translator)
+	return toMatchGroups(groups, rules, owningIngressIdx, translator)
+}
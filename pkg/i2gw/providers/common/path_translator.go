@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// PathSemantics is the Gateway API match and filters a PathTranslator
+// resolves an Ingress path to, plus an optional suffix distinguishing it,
+// for grouping purposes, from other paths that resolve to the same
+// HTTPPathMatch but carry different vendor semantics.
+type PathSemantics struct {
+	Match   gatewayv1.HTTPPathMatch
+	Filters []gatewayv1.HTTPRouteFilter
+
+	// GroupKeySuffix, when non-empty, is appended to the group key derived
+	// from Match so that otherwise-identical matches (same Type and Value)
+	// produced by different vendor annotations are not collapsed into the
+	// same HTTPRoute rule. For example Traefik's PathPrefixStrip on /api
+	// and a plain PathPrefix on /api both resolve to a PathPrefix match,
+	// but only the former should carry a URLRewrite filter, so they must
+	// stay in separate groups.
+	GroupKeySuffix string
+}
+
+// PathTranslator maps an Ingress path, together with the annotations of its
+// owning Ingress, to the Gateway API match and filters that reproduce its
+// routing semantics. Providers whose annotations change path semantics
+// (Traefik's traefik.frontend.rule.type, nginx's use-regex/rewrite-target,
+// and so on) register one so that groupPathsWithTranslator keys paths by
+// their translated semantics instead of the raw PathType/Path pair.
+type PathTranslator interface {
+	Translate(path networkingv1.HTTPIngressPath, annotations map[string]string) PathSemantics
+}
+
+// DefaultPathTranslator resolves an Ingress path's PathType/Path directly
+// to the equivalent Gateway API HTTPPathMatch, with no additional filters.
+// toMatchGroups falls back to it to resolve each path's Match when given a
+// nil translator. groupPaths does not use it: with a nil translator it keys
+// paths by their raw PathType/Path instead.
+type DefaultPathTranslator struct{}
+
+// Translate implements PathTranslator.
+func (DefaultPathTranslator) Translate(path networkingv1.HTTPIngressPath, _ map[string]string) PathSemantics {
+	return PathSemantics{Match: defaultHTTPPathMatch(path)}
+}
+
+// defaultHTTPPathMatch converts an Ingress PathType into the matching
+// Gateway API HTTPPathMatch, with no vendor-specific reinterpretation.
+// Gateway API has no "implementation specific" match type, so
+// PathTypeImplementationSpecific (and an unset PathType) falls back to
+// PathMatchPathPrefix, the closest approximation of Ingress' historical
+// default matching behavior.
+func defaultHTTPPathMatch(path networkingv1.HTTPIngressPath) gatewayv1.HTTPPathMatch {
+	matchType := gatewayv1.PathMatchPathPrefix
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		case networkingv1.PathTypePrefix:
+			matchType = gatewayv1.PathMatchPathPrefix
+		}
+	}
+	return gatewayv1.HTTPPathMatch{
+		Type:  PtrTo(matchType),
+		Value: PtrTo(path.Path),
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ingressRule wraps a networkingv1.IngressRule together with the annotations
+// of the Ingress it was read from, so that later stages (e.g. groupPaths) can
+// make merging decisions that depend on annotations carried by the owning
+// Ingress rather than the rule itself.
+type ingressRule struct {
+	networkingv1.IngressRule
+
+	// annotations are the annotations of the Ingress this rule belongs to.
+	// It is nil for rules that were not sourced from an annotated Ingress
+	// (for example in unit tests that only exercise path grouping).
+	annotations map[string]string
+}
+
+// ingressPath associates a single HTTPIngressPath with the indices of the
+// ingressRule/path it was read from, so conversion errors and annotations
+// can be traced back to their source.
+type ingressPath struct {
+	ruleIdx  int
+	pathIdx  int
+	ruleType string
+	path     networkingv1.HTTPIngressPath
+}
+
+// pathsByMatchGroupType groups all the ingressPaths that should be collapsed
+// into a single Gateway API HTTPRouteRule, keyed by their PathType/Path (or,
+// with a PathTranslator, by the translated match). Canary merging only tags
+// the group through canary; it does not change its key.
+type pathsByMatchGroupType struct {
+	key   string
+	paths []ingressPath
+
+	// canary holds the resolved canary-merge state for this group, if the
+	// paths it contains were produced by an nginx canary annotation pair.
+	// It is nil for groups with no canary involvement.
+	canary *canaryMatch
+
+	// validationErrs collects non-fatal warnings discovered while grouping,
+	// such as a canary Ingress mixing weight and header/cookie selection.
+	validationErrs field.ErrorList
+}
+
+// groupPaths groups the paths of the given ingressRules by their PathType
+// and Path, preserving the order in which each distinct key was first seen.
+// Paths that share a key are later collapsed into a single HTTPRoute rule by
+// the provider-specific converters. It uses no PathTranslator (not even
+// DefaultPathTranslator) and keys paths by their raw PathType/Path; callers
+// that need a provider's vendor path semantics (e.g.
+// Traefik's rule.type or nginx's regex paths) should call
+// groupPathsWithTranslator instead.
+func groupPaths(rules []ingressRule) []pathsByMatchGroupType {
+	return groupPathsWithTranslator(rules, nil)
+}
+
+// groupPathsWithTranslator is groupPaths, but keys each path by the match
+// the given PathTranslator resolves it to rather than its raw PathType/Path.
+// A nil translator falls back to the raw PathType/Path key.
+//
+// Grouping is always scoped to a single rule.Host first: two rules that
+// resolve to the same path key but belong to different hosts must never
+// collapse into one group, since that would merge unrelated hosts' backends
+// into a single HTTPRouteRule. The host is folded into the lookup key only;
+// the exported/stored key field stays the path-only key so callers that
+// display or compare it (e.g. conflict descriptions) don't need to care
+// about hosts that were never ambiguous to begin with.
+func groupPathsWithTranslator(rules []ingressRule, translator PathTranslator) []pathsByMatchGroupType {
+	groupsByKey := map[string]*pathsByMatchGroupType{}
+	orderedKeys := []string{}
+
+	for ruleIdx, rule := range rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for pathIdx, path := range rule.HTTP.Paths {
+			pathKey := pathMatchKey(path, rules[ruleIdx].annotations, translator)
+			lookupKey := rule.Host + "\x00" + pathKey
+
+			group, ok := groupsByKey[lookupKey]
+			if !ok {
+				group = &pathsByMatchGroupType{key: pathKey}
+				groupsByKey[lookupKey] = group
+				orderedKeys = append(orderedKeys, lookupKey)
+			}
+
+			group.paths = append(group.paths, ingressPath{
+				ruleIdx:  ruleIdx,
+				pathIdx:  pathIdx,
+				ruleType: "http",
+				path:     path,
+			})
+		}
+	}
+
+	groups := make([]pathsByMatchGroupType, 0, len(orderedKeys))
+	for _, key := range orderedKeys {
+		groups = append(groups, *groupsByKey[key])
+	}
+
+	return annotateCanaryGroups(groups, rules)
+}
+
+// pathMatchKey returns the grouping key for a path. With a nil translator it
+// is the historical PathType/Path pair, kept distinct from the translator-keyed
+// format (see DefaultPathTranslator's own grouping below) so existing callers
+// that group with a nil translator keep their pre-PathTranslator key shape.
+// With a translator it is derived from the resolved HTTPPathMatch plus its
+// GroupKeySuffix, so that paths which are semantically different despite
+// sharing a PathType/Path (e.g. Traefik's PathPrefixStrip vs PathPrefix) land
+// in distinct groups.
+func pathMatchKey(path networkingv1.HTTPIngressPath, annotations map[string]string, translator PathTranslator) string {
+	if translator == nil {
+		pathType := networkingv1.PathTypeImplementationSpecific
+		if path.PathType != nil {
+			pathType = *path.PathType
+		}
+		return fmt.Sprintf("%s/%s", pathType, path.Path)
+	}
+
+	sem := translator.Translate(path, annotations)
+	matchType := gatewayv1.PathMatchPathPrefix
+	if sem.Match.Type != nil {
+		matchType = *sem.Match.Type
+	}
+	value := ""
+	if sem.Match.Value != nil {
+		value = *sem.Match.Value
+	}
+	key := fmt.Sprintf("%s/%s", matchType, value)
+	if sem.GroupKeySuffix != "" {
+		key = key + "#" + sem.GroupKeySuffix
+	}
+	return key
+}
+
+// PtrTo returns a pointer to a copy of the given value. It is a small,
+// generic stand-in for the type-specific pointer helpers (e.g.
+// ptr.To[T]) used throughout the provider tests and fixtures.
+func PtrTo[T any](a T) *T {
+	return &a
+}
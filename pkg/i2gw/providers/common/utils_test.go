@@ -21,6 +21,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func TestGroupPaths(t *testing.T) {
@@ -40,7 +42,7 @@ func TestGroupPaths(t *testing.T) {
 			name: "1 rule with 1 match",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -91,7 +93,7 @@ func TestGroupPaths(t *testing.T) {
 			name: "1 rule, multiple matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -176,7 +178,7 @@ func TestGroupPaths(t *testing.T) {
 			name: "multiple rules with single matches, same path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -198,7 +200,7 @@ func TestGroupPaths(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -262,11 +264,108 @@ func TestGroupPaths(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "same path on different hosts must not merge into one group",
+			rules: []ingressRule{
+				{
+					IngressRule: networkingv1.IngressRule{
+						Host: "a.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "svc-a",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						Host: "b.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "svc-b",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []pathsByMatchGroupType{
+				{
+					key: "Prefix//",
+					paths: []ingressPath{
+						{
+							ruleIdx:  0,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "svc-a",
+										Port: networkingv1.ServiceBackendPort{
+											Number: 80,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					key: "Prefix//",
+					paths: []ingressPath{
+						{
+							ruleIdx:  1,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "svc-b",
+										Port: networkingv1.ServiceBackendPort{
+											Number: 80,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "multiple rules with single matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -288,7 +387,7 @@ func TestGroupPaths(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -361,7 +460,7 @@ func TestGroupPaths(t *testing.T) {
 			name: "multiple rules with multiple matches, mixed paths",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -395,7 +494,7 @@ func TestGroupPaths(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					IngressRule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -515,6 +614,371 @@ func TestGroupPaths(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "canary-weight annotation merges same path into a weighted group",
+			rules: []ingressRule{
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "primary",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "canary",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					annotations: map[string]string{
+						CanaryAnnotation:       "true",
+						CanaryWeightAnnotation: "30",
+					},
+				},
+			},
+			expected: []pathsByMatchGroupType{
+				{
+					key: "Prefix//test",
+					paths: []ingressPath{
+						{
+							ruleIdx:  0,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "primary",
+										Port: networkingv1.ServiceBackendPort{
+											Number: 80,
+										},
+									},
+								},
+							},
+						},
+						{
+							ruleIdx:  1,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: "canary",
+										Port: networkingv1.ServiceBackendPort{
+											Number: 80,
+										},
+									},
+								},
+							},
+						},
+					},
+					canary: &canaryMatch{
+						strategy:       canaryStrategyWeight,
+						primaryPathIdx: 0,
+						canaryPathIdx:  1,
+						weight:         30,
+						weightTotal:    100,
+					},
+				},
+			},
+		},
+		{
+			name: "two primaries and a canary sharing a key fall back to a plain multi-backend group",
+			rules: []ingressRule{
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "primary-a", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "primary-b", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+					annotations: map[string]string{
+						CanaryAnnotation:       "true",
+						CanaryWeightAnnotation: "30",
+					},
+				},
+			},
+			expected: []pathsByMatchGroupType{
+				{
+					key: "Prefix//test",
+					paths: []ingressPath{
+						{
+							ruleIdx:  0,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "primary-a", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+						{
+							ruleIdx:  1,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "primary-b", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+						{
+							ruleIdx:  2,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+					},
+					validationErrs: field.ErrorList{
+						field.Invalid(field.NewPath("spec", "rules"), "Prefix//test",
+							"match group has 2 primary and 1 canary path(s); canary merging requires exactly one of each, falling back to a plain multi-backend rule"),
+					},
+				},
+			},
+		},
+		{
+			name: "out-of-range canary-weight falls back to a plain multi-backend group",
+			rules: []ingressRule{
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "primary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+					annotations: map[string]string{
+						CanaryAnnotation:       "true",
+						CanaryWeightAnnotation: "150",
+					},
+				},
+			},
+			expected: []pathsByMatchGroupType{
+				{
+					key: "Prefix//test",
+					paths: []ingressPath{
+						{
+							ruleIdx:  0,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "primary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+						{
+							ruleIdx:  1,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+					},
+					validationErrs: field.ErrorList{
+						field.Invalid(field.NewPath("metadata", "annotations"), CanaryWeightAnnotation,
+							"canary-weight 150 is outside the valid 0-100 range; falling back to a plain multi-backend rule for this match group"),
+					},
+				},
+			},
+		},
+		{
+			name: "canary-weight within 0-100 but over a non-default canary-weight-total falls back to a plain multi-backend group",
+			rules: []ingressRule{
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "primary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					IngressRule: networkingv1.IngressRule{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/test",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+										},
+									},
+								},
+							},
+						},
+					},
+					annotations: map[string]string{
+						CanaryAnnotation:            "true",
+						CanaryWeightAnnotation:      "80",
+						CanaryWeightTotalAnnotation: "50",
+					},
+				},
+			},
+			expected: []pathsByMatchGroupType{
+				{
+					key: "Prefix//test",
+					paths: []ingressPath{
+						{
+							ruleIdx:  0,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "primary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+						{
+							ruleIdx:  1,
+							pathIdx:  0,
+							ruleType: "http",
+							path: networkingv1.HTTPIngressPath{
+								Path:     "/test",
+								PathType: &iPrefix,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "canary", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							},
+						},
+					},
+					validationErrs: field.ErrorList{
+						field.Invalid(field.NewPath("metadata", "annotations"), CanaryWeightAnnotation,
+							"canary-weight 80 is outside the valid 0-50 range; falling back to a plain multi-backend rule for this match group"),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -525,3 +989,97 @@ func TestGroupPaths(t *testing.T) {
 		})
 	}
 }
+
+// stripPrefixTranslator is a stand-in for a vendor PathTranslator (e.g.
+// Traefik's) that reinterprets PathPrefix paths annotated with "strip" as
+// stripping, rather than preserving, the matched prefix.
+type stripPrefixTranslator struct{}
+
+func (stripPrefixTranslator) Translate(path networkingv1.HTTPIngressPath, annotations map[string]string) PathSemantics {
+	pathType := gatewayv1.PathMatchPathPrefix
+	if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+		pathType = gatewayv1.PathMatchExact
+	}
+	sem := PathSemantics{Match: gatewayv1.HTTPPathMatch{Type: PtrTo(pathType), Value: PtrTo(path.Path)}}
+	if annotations["rule-type"] == "strip" {
+		sem.GroupKeySuffix = "strip"
+	}
+	return sem
+}
+
+func TestGroupPathsWithTranslator(t *testing.T) {
+	rules := []ingressRule{
+		{
+			IngressRule: networkingv1.IngressRule{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/api",
+							PathType: PtrTo(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "plain", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							},
+						}},
+					},
+				},
+			},
+		},
+		{
+			IngressRule: networkingv1.IngressRule{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/api",
+							PathType: PtrTo(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "stripped", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							},
+						}},
+					},
+				},
+			},
+			annotations: map[string]string{"rule-type": "strip"},
+		},
+	}
+
+	groups := groupPathsWithTranslator(rules, stripPrefixTranslator{})
+
+	require.Len(t, groups, 2, "a PathPrefix and its strip-annotated variant must not collapse into the same group")
+	require.Equal(t, "PathPrefix//api", groups[0].key)
+	require.Equal(t, "PathPrefix//api#strip", groups[1].key)
+}
+
+// TestGroupPathsWithNilTranslatorKeyDivergesFromDefault documents that a nil
+// translator and an explicit DefaultPathTranslator are NOT equivalent: nil
+// keeps the historical raw networkingv1.PathType key (e.g. "Prefix//test"),
+// preserving the grouping of callers that predate PathTranslator, while
+// DefaultPathTranslator keys by the translated gatewayv1.PathMatchType (e.g.
+// "PathPrefix//test") like every other PathTranslator. Both still group the
+// single path here into one group; only the key shape differs.
+func TestGroupPathsWithNilTranslatorKeyDivergesFromDefault(t *testing.T) {
+	rules := []ingressRule{
+		{
+			IngressRule: networkingv1.IngressRule{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/test",
+							PathType: PtrTo(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "test", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	nilGroups := groupPaths(rules)
+	defaultGroups := groupPathsWithTranslator(rules, DefaultPathTranslator{})
+
+	require.Len(t, nilGroups, 1)
+	require.Len(t, defaultGroups, 1)
+	require.Equal(t, "Prefix//test", nilGroups[0].key)
+	require.Equal(t, "PathPrefix//test", defaultGroups[0].key)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nginx converts ingress-nginx flavored Ingress resources, including
+// their canary annotation family, into Gateway API HTTPRoutes.
+package nginx
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+// buildHTTPRouteRules converts the MatchGroups produced by
+// common.GroupIngressPaths into Gateway API HTTPRouteRules. Groups tagged
+// with a weight-based CanaryMatch collapse into a single rule carrying two
+// weighted backendRefs; groups tagged with a header/cookie CanaryMatch are
+// split into a canary-only rule (returned first, so it is evaluated ahead of
+// the primary rule per Gateway API ordering) and the unchanged primary rule.
+// Any path whose Backend is a Resource rather than a Service has no
+// Gateway API BackendRef equivalent; it is dropped from its rule and
+// reported in the returned field.ErrorList rather than converted.
+func buildHTTPRouteRules(ingresses []networkingv1.Ingress, groups []common.MatchGroup) ([]gatewayv1.HTTPRouteRule, field.ErrorList) {
+	rules := make([]gatewayv1.HTTPRouteRule, 0, len(groups))
+	var notifications field.ErrorList
+
+	for _, group := range groups {
+		if group.Canary == nil {
+			rule, notifs := buildPlainRule(ingresses, group)
+			rules = append(rules, rule)
+			notifications = append(notifications, notifs...)
+			continue
+		}
+
+		switch group.Canary.Strategy {
+		case "header", "cookie":
+			rule, notifs := buildCanaryConditionalRule(ingresses, group)
+			rules = append(rules, rule)
+			notifications = append(notifications, notifs...)
+			rule, notifs = buildPrimaryOnlyRule(ingresses, group)
+			rules = append(rules, rule)
+			notifications = append(notifications, notifs...)
+		default:
+			rule, notifs := buildWeightedRule(ingresses, group)
+			rules = append(rules, rule)
+			notifications = append(notifications, notifs...)
+		}
+	}
+
+	return rules, notifications
+}
+
+// buildPlainRule builds a rule for a MatchGroup with no canary involvement:
+// one backendRef per path in the group, as the pre-canary converter did.
+func buildPlainRule(ingresses []networkingv1.Ingress, group common.MatchGroup) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{pathMatch(group.Paths[0])},
+		Filters: group.Paths[0].Filters,
+	}
+	var notifications field.ErrorList
+	for _, p := range group.Paths {
+		ref, ok := backendRef(ingresses, p, nil)
+		if !ok {
+			notifications = append(notifications, resourceBackendNotification(p))
+			continue
+		}
+		rule.BackendRefs = append(rule.BackendRefs, ref)
+	}
+	return rule, notifications
+}
+
+// buildWeightedRule collapses the primary and canary paths of a
+// weight-strategy MatchGroup into a single rule with two weighted
+// backendRefs: the primary backend gets WeightTotal-Weight, the canary
+// backend gets Weight.
+func buildWeightedRule(ingresses []networkingv1.Ingress, group common.MatchGroup) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	primary := group.Paths[group.Canary.PrimaryPathIdx]
+	canary := group.Paths[group.Canary.CanaryPathIdx]
+
+	primaryWeight := group.Canary.WeightTotal - group.Canary.Weight
+	canaryWeight := group.Canary.Weight
+
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{pathMatch(primary)},
+		Filters: primary.Filters,
+	}
+	var notifications field.ErrorList
+	if ref, ok := backendRef(ingresses, primary, common.PtrTo(primaryWeight)); ok {
+		rule.BackendRefs = append(rule.BackendRefs, ref)
+	} else {
+		notifications = append(notifications, resourceBackendNotification(primary))
+	}
+	if ref, ok := backendRef(ingresses, canary, common.PtrTo(canaryWeight)); ok {
+		rule.BackendRefs = append(rule.BackendRefs, ref)
+	} else {
+		notifications = append(notifications, resourceBackendNotification(canary))
+	}
+	return rule, notifications
+}
+
+// buildCanaryConditionalRule builds the canary-only rule for a header/cookie
+// strategy MatchGroup: the path match plus the extra header or cookie
+// condition, routing entirely (weight 100) to the canary backend.
+func buildCanaryConditionalRule(ingresses []networkingv1.Ingress, group common.MatchGroup) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	canary := group.Paths[group.Canary.CanaryPathIdx]
+	match := pathMatch(canary)
+
+	if group.Canary.Strategy == "header" {
+		match.Headers = []gatewayv1.HTTPHeaderMatch{{
+			Type:  common.PtrTo(gatewayv1.HeaderMatchExact),
+			Name:  gatewayv1.HTTPHeaderName(group.Canary.HeaderName),
+			Value: group.Canary.HeaderValue,
+		}}
+	} else {
+		match.Headers = []gatewayv1.HTTPHeaderMatch{{
+			Type:  common.PtrTo(gatewayv1.HeaderMatchRegularExpression),
+			Name:  "Cookie",
+			Value: group.Canary.CookieName + "=.*",
+		}}
+	}
+
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{match},
+		Filters: canary.Filters,
+	}
+	ref, ok := backendRef(ingresses, canary, common.PtrTo(int32(100)))
+	if !ok {
+		return rule, field.ErrorList{resourceBackendNotification(canary)}
+	}
+	rule.BackendRefs = []gatewayv1.HTTPBackendRef{ref}
+	return rule, nil
+}
+
+// buildPrimaryOnlyRule builds the fallback rule of a header/cookie strategy
+// MatchGroup: the unconditional path match routing to the primary backend.
+func buildPrimaryOnlyRule(ingresses []networkingv1.Ingress, group common.MatchGroup) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	primary := group.Paths[group.Canary.PrimaryPathIdx]
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{pathMatch(primary)},
+		Filters: primary.Filters,
+	}
+	ref, ok := backendRef(ingresses, primary, nil)
+	if !ok {
+		return rule, field.ErrorList{resourceBackendNotification(primary)}
+	}
+	rule.BackendRefs = []gatewayv1.HTTPBackendRef{ref}
+	return rule, nil
+}
+
+// resourceBackendNotification reports that a path's Backend is a Resource
+// reference rather than a Service, which has no Gateway API BackendRef
+// equivalent.
+func resourceBackendNotification(p common.MatchGroupPath) *field.Error {
+	return field.Invalid(field.NewPath("spec", "rules").Child("http", "paths").Child("backend"),
+		p.Path.Backend.Resource, "Resource backends have no Gateway API BackendRef equivalent and were not converted")
+}
+
+// pathMatch returns the HTTPRouteMatch for a grouped path, preferring the
+// PathTranslator-resolved Match, and falling back to a plain conversion of
+// the Ingress PathType when that Match has no Type set.
+func pathMatch(p common.MatchGroupPath) gatewayv1.HTTPRouteMatch {
+	if p.Match.Type != nil {
+		match := p.Match
+		return gatewayv1.HTTPRouteMatch{Path: &match}
+	}
+
+	pathType := gatewayv1.PathMatchPathPrefix
+	if p.Path.PathType != nil && *p.Path.PathType == networkingv1.PathTypeExact {
+		pathType = gatewayv1.PathMatchExact
+	}
+	return gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{
+			Type:  common.PtrTo(pathType),
+			Value: common.PtrTo(p.Path.Path),
+		},
+	}
+}
+
+// backendRef builds the BackendRef for p, reporting ok=false when p's
+// Backend is a Resource reference rather than a Service -- Gateway API's
+// BackendRef has no Resource-backend equivalent.
+func backendRef(ingresses []networkingv1.Ingress, p common.MatchGroupPath, weight *int32) (ref gatewayv1.HTTPBackendRef, ok bool) {
+	svc := p.Path.Backend.Service
+	if svc == nil {
+		return gatewayv1.HTTPBackendRef{}, false
+	}
+	ref = gatewayv1.HTTPBackendRef{
+		BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(svc.Name),
+				Port: common.PtrTo(gatewayv1.PortNumber(svc.Port.Number)),
+			},
+		},
+	}
+	if weight != nil {
+		ref.Weight = weight
+	}
+	return ref, true
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+func ingressWithPath(name, svc string, annotations map[string]string) networkingv1.Ingress {
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/test",
+							PathType: common.PtrTo(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: svc,
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestBuildHTTPRouteRulesCanaryWeight(t *testing.T) {
+	ingresses := []networkingv1.Ingress{
+		ingressWithPath("primary", "primary-svc", nil),
+		ingressWithPath("canary", "canary-svc", map[string]string{
+			common.CanaryAnnotation:       "true",
+			common.CanaryWeightAnnotation: "30",
+		}),
+	}
+
+	groups := common.GroupIngressPaths(ingresses, Translator{})
+	require.Len(t, groups, 1)
+	require.NotNil(t, groups[0].Canary)
+
+	rules, notifications := buildHTTPRouteRules(ingresses, groups)
+	require.Empty(t, notifications)
+	require.Len(t, rules, 1)
+	require.Len(t, rules[0].BackendRefs, 2)
+	require.Equal(t, int32(70), *rules[0].BackendRefs[0].Weight)
+	require.Equal(t, int32(30), *rules[0].BackendRefs[1].Weight)
+}
+
+func TestBuildHTTPRouteRulesResourceBackendIsSkippedNotCrashed(t *testing.T) {
+	ingresses := []networkingv1.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "resource-backed"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     "/test",
+								PathType: common.PtrTo(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Resource: &corev1.TypedLocalObjectReference{
+										APIGroup: common.PtrTo("k8s.example.com"),
+										Kind:     "StorageBucket",
+										Name:     "my-bucket",
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	groups := common.GroupIngressPaths(ingresses, Translator{})
+	require.Len(t, groups, 1)
+
+	rules, notifications := buildHTTPRouteRules(ingresses, groups)
+	require.Len(t, rules, 1)
+	require.Empty(t, rules[0].BackendRefs)
+	require.Len(t, notifications, 1)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+const (
+	useRegexAnnotation      = "nginx.ingress.kubernetes.io/use-regex"
+	rewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+)
+
+// Translator is the nginx ingress-controller common.PathTranslator. It
+// turns nginx.ingress.kubernetes.io/use-regex into a
+// PathMatchRegularExpression match (when EnableRegexPaths is set, since not
+// every Gateway API implementation supports regex path matching) and
+// nginx.ingress.kubernetes.io/rewrite-target into a URLRewrite filter.
+type Translator struct {
+	// EnableRegexPaths gates translating use-regex paths into
+	// PathMatchRegularExpression matches. Implementations that don't
+	// support regex path matching should leave this false, in which case
+	// regex paths fall back to defaultMatch's PathType-based match.
+	EnableRegexPaths bool
+}
+
+// Translate implements common.PathTranslator.
+func (t Translator) Translate(path networkingv1.HTTPIngressPath, annotations map[string]string) common.PathSemantics {
+	sem := common.PathSemantics{Match: defaultMatch(path)}
+
+	if t.EnableRegexPaths && annotations[useRegexAnnotation] == "true" {
+		sem.Match = gatewayv1.HTTPPathMatch{
+			Type:  common.PtrTo(gatewayv1.PathMatchRegularExpression),
+			Value: common.PtrTo(path.Path),
+		}
+		sem.GroupKeySuffix = "regex"
+	}
+
+	if target, ok := annotations[rewriteTargetAnnotation]; ok && target != path.Path {
+		sem.Filters = append(sem.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:            gatewayv1.FullPathHTTPPathModifier,
+					ReplaceFullPath: common.PtrTo(target),
+				},
+			},
+		})
+		sem.GroupKeySuffix += "#rewrite=" + target
+	}
+
+	return sem
+}
+
+// defaultMatch falls back to PathMatchPathPrefix for PathTypeImplementationSpecific
+// (and an unset PathType), since Gateway API has no "implementation specific"
+// match type.
+func defaultMatch(path networkingv1.HTTPIngressPath) gatewayv1.HTTPPathMatch {
+	matchType := gatewayv1.PathMatchPathPrefix
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		case networkingv1.PathTypePrefix:
+			matchType = gatewayv1.PathMatchPathPrefix
+		}
+	}
+	return gatewayv1.HTTPPathMatch{
+		Type:  common.PtrTo(matchType),
+		Value: common.PtrTo(path.Path),
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nginx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+func TestTranslateRegexPathRequiresFeatureFlag(t *testing.T) {
+	path := networkingv1.HTTPIngressPath{
+		Path:     "/api/.*",
+		PathType: common.PtrTo(networkingv1.PathTypePrefix),
+	}
+	annotations := map[string]string{useRegexAnnotation: "true"}
+
+	withoutFlag := Translator{}.Translate(path, annotations)
+	require.Equal(t, gatewayv1.PathMatchPathPrefix, *withoutFlag.Match.Type)
+
+	withFlag := Translator{EnableRegexPaths: true}.Translate(path, annotations)
+	require.Equal(t, gatewayv1.PathMatchRegularExpression, *withFlag.Match.Type)
+	require.Equal(t, "regex", withFlag.GroupKeySuffix)
+}
+
+func TestTranslateRewriteTarget(t *testing.T) {
+	path := networkingv1.HTTPIngressPath{
+		Path:     "/old",
+		PathType: common.PtrTo(networkingv1.PathTypePrefix),
+	}
+
+	sem := Translator{}.Translate(path, map[string]string{rewriteTargetAnnotation: "/new"})
+
+	require.Len(t, sem.Filters, 1)
+	require.Equal(t, "/new", *sem.Filters[0].URLRewrite.Path.ReplaceFullPath)
+}
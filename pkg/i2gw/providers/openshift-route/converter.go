@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"strconv"
+
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+// ConversionResult is the set of Gateway API objects, and any non-fatal
+// notifications, produced from converting a list of Routes.
+type ConversionResult struct {
+	HTTPRouteRules     []gatewayv1.HTTPRouteRule
+	Listeners          []gatewayv1.Listener
+	BackendTLSPolicies []gatewayv1alpha3.BackendTLSPolicy
+	Notifications      field.ErrorList
+}
+
+// Convert adapts the given Routes into HTTPRoute rules (with weighted
+// backendRefs across spec.to/spec.alternateBackends), Gateway listeners (one
+// per distinct host and TLS termination mode), and a BackendTLSPolicy per reencrypt
+// Route. Unsupported combinations, such as passthrough termination combined
+// with path-based routing, are surfaced as Notifications rather than
+// silently dropped.
+func Convert(routes []routev1.Route) ConversionResult {
+	ingresses := ToIngresses(routes)
+	groups := common.GroupIngressPaths(ingresses, nil)
+
+	result := ConversionResult{}
+	seenListeners := map[string]bool{}
+
+	for _, rt := range routes {
+		if err := validateRoute(rt); err != nil {
+			result.Notifications = append(result.Notifications, err)
+		}
+
+		listenerName := listenerKey(rt)
+		if !seenListeners[listenerName] {
+			seenListeners[listenerName] = true
+			result.Listeners = append(result.Listeners, buildListener(rt))
+		}
+
+		if rt.Spec.TLS != nil && rt.Spec.TLS.Termination == routev1.TLSTerminationReencrypt {
+			result.BackendTLSPolicies = append(result.BackendTLSPolicies, buildBackendTLSPolicy(rt))
+		}
+	}
+
+	for _, group := range groups {
+		rule, notifications := buildRule(ingresses, group)
+		result.HTTPRouteRules = append(result.HTTPRouteRules, rule)
+		result.Notifications = append(result.Notifications, notifications...)
+	}
+
+	return result
+}
+
+// validateRoute flags Route configurations that have no faithful Gateway
+// API equivalent, namely passthrough TLS termination (which requires the
+// backend to see the original TLS handshake) combined with path-based
+// routing (which requires the Gateway to inspect the decrypted HTTP
+// request).
+func validateRoute(rt routev1.Route) *field.Error {
+	if rt.Spec.TLS != nil && rt.Spec.TLS.Termination == routev1.TLSTerminationPassthrough && rt.Spec.Path != "" && rt.Spec.Path != "/" {
+		return field.Invalid(field.NewPath("spec", "path"), rt.Spec.Path,
+			"passthrough TLS termination cannot be combined with path-based routing; the Gateway cannot inspect the encrypted request")
+	}
+	return nil
+}
+
+func listenerKey(rt routev1.Route) string {
+	mode := ""
+	if rt.Spec.TLS != nil {
+		mode = string(rt.Spec.TLS.Termination)
+	}
+	return routeHost(rt) + "|" + mode
+}
+
+// buildListener maps a Route's TLS termination to the Gateway listener that
+// reproduces it: edge and reencrypt both terminate TLS at the Gateway
+// (reencrypt additionally re-encrypts to the backend via a
+// BackendTLSPolicy), while passthrough forwards the raw TLS stream
+// untouched.
+func buildListener(rt routev1.Route) gatewayv1.Listener {
+	listener := gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(sanitizeName(rt.Name)),
+		Hostname: common.PtrTo(gatewayv1.Hostname(routeHost(rt))),
+		Protocol: gatewayv1.HTTPProtocolType,
+		Port:     80,
+	}
+
+	if rt.Spec.TLS == nil {
+		return listener
+	}
+
+	listener.Protocol = gatewayv1.HTTPSProtocolType
+	listener.Port = 443
+	listener.TLS = &gatewayv1.GatewayTLSConfig{}
+
+	switch rt.Spec.TLS.Termination {
+	case routev1.TLSTerminationPassthrough:
+		listener.Protocol = gatewayv1.TLSProtocolType
+		listener.TLS.Mode = common.PtrTo(gatewayv1.TLSModePassthrough)
+	default: // edge, reencrypt
+		listener.TLS.Mode = common.PtrTo(gatewayv1.TLSModeTerminate)
+	}
+
+	return listener
+}
+
+// buildBackendTLSPolicy reencrypts traffic from the Gateway to the backend
+// for a reencrypt-terminated Route, which terminates client TLS at the
+// Gateway like edge but additionally requires a new TLS connection to the
+// backend Service.
+func buildBackendTLSPolicy(rt routev1.Route) gatewayv1alpha3.BackendTLSPolicy {
+	return gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: objectMetaFor(rt),
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{
+					Group: "",
+					Kind:  "Service",
+					Name:  gatewayv1.ObjectName(rt.Spec.To.Name),
+				},
+			}},
+		},
+	}
+}
+
+// buildRule collapses a MatchGroup produced from a Route's spec.to/
+// spec.alternateBackends into a single HTTPRouteRule, weighting each
+// backendRef per the route.openshift.io/backend-weight annotation ToIngresses
+// attached to each synthetic path. ToIngresses only ever synthesizes
+// Service-backed IngressBackends, but a path with a Resource backend is
+// reported rather than dereferenced, since GroupIngressPaths/MatchGroup are
+// shared with providers that do carry Resource backends.
+func buildRule(ingresses []networkingv1.Ingress, group common.MatchGroup) (gatewayv1.HTTPRouteRule, field.ErrorList) {
+	rule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{
+				Type:  common.PtrTo(gatewayv1.PathMatchPathPrefix),
+				Value: common.PtrTo(group.Paths[0].Path.Path),
+			},
+		}},
+	}
+
+	var notifications field.ErrorList
+	for _, p := range group.Paths {
+		svc := p.Path.Backend.Service
+		if svc == nil {
+			notifications = append(notifications, field.Invalid(field.NewPath("spec", "to"),
+				p.Path.Backend.Resource, "Resource backends have no Gateway API BackendRef equivalent and were not converted"))
+			continue
+		}
+		weight, _ := strconv.Atoi(ingresses[p.IngressIdx].Annotations[weightAnnotation])
+		rule.BackendRefs = append(rule.BackendRefs, gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(svc.Name),
+					Port: common.PtrTo(gatewayv1.PortNumber(svc.Port.Number)),
+				},
+				Weight: common.PtrTo(int32(weight)),
+			},
+		})
+	}
+
+	return rule, notifications
+}
+
+func objectMetaFor(rt routev1.Route) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: rt.Name + "-backend-tls", Namespace: rt.Namespace}
+}
+
+func sanitizeName(name string) string {
+	if name == "" {
+		return "route"
+	}
+	return name
+}
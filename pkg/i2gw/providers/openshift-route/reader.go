@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package route converts route.openshift.io/v1 Route resources into Gateway
+// API resources by first adapting them into the same intermediate
+// networkingv1.Ingress shape that common.GroupIngressPaths consumes, so
+// Routes and Ingresses targeting the same host/path are grouped uniformly.
+package route
+
+import (
+	"strconv"
+
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// weightAnnotation carries a Route backend's relative weight on its
+// synthetic Ingress through common.GroupIngressPaths, in the same spirit as
+// the nginx canary-weight annotation. The shared grouping pipeline does not
+// read it; buildRule reads it back to weight each backendRef.
+const weightAnnotation = "route.openshift.io/backend-weight"
+
+// defaultWeight is the weight OpenShift assigns a backend with no explicit
+// Weight set.
+const defaultWeight = 100
+
+// ToIngresses adapts the given Routes into synthetic, single-rule
+// networkingv1.Ingresses: one per (Route, backend) pair, so that a Route's
+// spec.to and spec.alternateBackends become sibling paths sharing the same
+// PathType/Path key and are collapsed by groupPaths exactly like an
+// ingress-nginx canary pair is. The Route's name is preserved on each
+// synthetic Ingress so converter errors can be traced back to it.
+func ToIngresses(routes []routev1.Route) []networkingv1.Ingress {
+	ingresses := make([]networkingv1.Ingress, 0, len(routes))
+
+	for _, rt := range routes {
+		pathType := networkingv1.PathTypeImplementationSpecific
+		path := rt.Spec.Path
+		if path == "" {
+			path = "/"
+			pathType = networkingv1.PathTypePrefix
+		}
+
+		for _, backend := range allBackends(rt) {
+			ingresses = append(ingresses, networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      rt.Name,
+					Namespace: rt.Namespace,
+					Annotations: map[string]string{
+						weightAnnotation: weightString(backend),
+					},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{
+						Host: routeHost(rt),
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     path,
+									PathType: &pathType,
+									Backend:  toIngressBackend(backend, rt.Spec.Port),
+								}},
+							},
+						},
+					}},
+				},
+			})
+		}
+	}
+
+	return ingresses
+}
+
+// allBackends returns the Route's primary backend (spec.to) followed by its
+// spec.alternateBackends, in that order, so the primary backend is always
+// the first/"primary" path within its groupPaths group.
+func allBackends(rt routev1.Route) []routev1.RouteTargetReference {
+	return append([]routev1.RouteTargetReference{rt.Spec.To}, rt.Spec.AlternateBackends...)
+}
+
+// routeHost resolves the hostname a Route's rules should be keyed by,
+// expanding a wildcard policy into a "*.domain" listener hostname.
+func routeHost(rt routev1.Route) string {
+	if rt.Spec.WildcardPolicy == routev1.WildcardPolicySubdomain {
+		if idx := firstDot(rt.Spec.Host); idx >= 0 {
+			return "*" + rt.Spec.Host[idx:]
+		}
+	}
+	return rt.Spec.Host
+}
+
+func firstDot(s string) int {
+	for i, c := range s {
+		if c == '.' {
+			return i
+		}
+	}
+	return -1
+}
+
+func toIngressBackend(ref routev1.RouteTargetReference, port *routev1.RoutePort) networkingv1.IngressBackend {
+	svcPort := networkingv1.ServiceBackendPort{}
+	if port != nil {
+		if port.TargetPort.Type == 0 { // intstr.Int
+			svcPort.Number = port.TargetPort.IntVal
+		} else {
+			svcPort.Name = port.TargetPort.StrVal
+		}
+	}
+	return networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: ref.Name,
+			Port: svcPort,
+		},
+	}
+}
+
+func weightString(ref routev1.RouteTargetReference) string {
+	w := defaultWeight
+	if ref.Weight != nil {
+		w = int(*ref.Weight)
+	}
+	return strconv.Itoa(w)
+}
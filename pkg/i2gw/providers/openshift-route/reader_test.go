@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestToIngressesSplitsAlternateBackendsIntoSiblingPaths(t *testing.T) {
+	routes := []routev1.Route{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: routev1.RouteSpec{
+				Host: "web.example.com",
+				To:   routev1.RouteTargetReference{Name: "web-v1", Weight: intPtr(80)},
+				AlternateBackends: []routev1.RouteTargetReference{
+					{Name: "web-v2", Weight: intPtr(20)},
+				},
+			},
+		},
+	}
+
+	ingresses := ToIngresses(routes)
+
+	require.Len(t, ingresses, 2)
+	require.Equal(t, "web-v1", ingresses[0].Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	require.Equal(t, "80", ingresses[0].Annotations[weightAnnotation])
+	require.Equal(t, "web-v2", ingresses[1].Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	require.Equal(t, "20", ingresses[1].Annotations[weightAnnotation])
+}
+
+func TestToIngressesExpandsWildcardHost(t *testing.T) {
+	routes := []routev1.Route{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "wild"},
+			Spec: routev1.RouteSpec{
+				Host:           "foo.example.com",
+				WildcardPolicy: routev1.WildcardPolicySubdomain,
+				To:             routev1.RouteTargetReference{Name: "wild-svc"},
+			},
+		},
+	}
+
+	ingresses := ToIngresses(routes)
+
+	require.Equal(t, "*.example.com", ingresses[0].Spec.Rules[0].Host)
+}
+
+func intPtr(i int32) *int32 { return &i }
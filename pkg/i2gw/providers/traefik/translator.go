@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package traefik converts Traefik's traefik.frontend.rule.type annotation
+// into Gateway API HTTPRoute matches and filters.
+package traefik
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+)
+
+const ruleTypeAnnotation = "traefik.frontend.rule.type"
+
+// Rule types recognized by the legacy Traefik v1 ingress controller.
+const (
+	ruleTypePath            = "Path"
+	ruleTypePathPrefix      = "PathPrefix"
+	ruleTypePathStrip       = "PathStrip"
+	ruleTypePathPrefixStrip = "PathPrefixStrip"
+)
+
+// Translator is the Traefik common.PathTranslator. PathStrip/PathPrefixStrip
+// rule types strip the matched path from the forwarded request, which
+// Gateway API expresses as a URLRewrite filter: an empty ReplacePrefixMatch
+// for PathPrefixStrip, a ReplaceFullPath of "/" for PathStrip. Path yields an
+// Exact match; PathPrefix (and a missing or unknown rule type) yields the
+// default match for the Ingress PathType.
+type Translator struct{}
+
+// Translate implements common.PathTranslator.
+func (Translator) Translate(path networkingv1.HTTPIngressPath, annotations map[string]string) common.PathSemantics {
+	ruleType, ok := annotations[ruleTypeAnnotation]
+	if !ok {
+		return common.PathSemantics{Match: defaultMatch(path)}
+	}
+
+	switch ruleType {
+	case ruleTypePathPrefixStrip:
+		return common.PathSemantics{
+			Match:          gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo(path.Path)},
+			Filters:        []gatewayv1.HTTPRouteFilter{stripPrefixFilter()},
+			GroupKeySuffix: "strip-prefix",
+		}
+	case ruleTypePathStrip:
+		return common.PathSemantics{
+			Match:          gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo(path.Path)},
+			Filters:        []gatewayv1.HTTPRouteFilter{stripFullPathFilter()},
+			GroupKeySuffix: "strip-exact",
+		}
+	case ruleTypePath:
+		return common.PathSemantics{Match: gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchExact), Value: common.PtrTo(path.Path)}}
+	case ruleTypePathPrefix:
+		fallthrough
+	default:
+		return common.PathSemantics{Match: defaultMatch(path)}
+	}
+}
+
+// stripPrefixFilter replaces the matched prefix with the empty string,
+// reproducing Traefik's PathPrefixStrip behavior of removing the matched
+// segment before forwarding the request to the backend. It must only be
+// paired with a PathPrefix match: Gateway API's validation requires
+// ReplacePrefixMatch to be used alongside a PathPrefix HTTPRouteMatch.
+func stripPrefixFilter() gatewayv1.HTTPRouteFilter {
+	return gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{
+				Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+				ReplacePrefixMatch: common.PtrTo(""),
+			},
+		},
+	}
+}
+
+// stripFullPathFilter rewrites the forwarded request path to "/", reproducing
+// Traefik's PathStrip behavior of removing the matched path before forwarding
+// to the backend. PathStrip matches the whole path exactly (unlike
+// PathPrefixStrip's prefix match), so it is paired with a PathMatchExact
+// HTTPRouteMatch; ReplaceFullPath is used rather than ReplacePrefixMatch
+// since Gateway API's validation only allows the latter alongside a
+// PathPrefix match.
+func stripFullPathFilter() gatewayv1.HTTPRouteFilter {
+	return gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{
+				Type:            gatewayv1.FullPathHTTPPathModifier,
+				ReplaceFullPath: common.PtrTo("/"),
+			},
+		},
+	}
+}
+
+// defaultMatch falls back to PathMatchPathPrefix for PathTypeImplementationSpecific
+// (and an unset PathType), since Gateway API has no "implementation specific"
+// match type.
+func defaultMatch(path networkingv1.HTTPIngressPath) gatewayv1.HTTPPathMatch {
+	matchType := gatewayv1.PathMatchPathPrefix
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		case networkingv1.PathTypePrefix:
+			matchType = gatewayv1.PathMatchPathPrefix
+		}
+	}
+	return gatewayv1.HTTPPathMatch{
+		Type:  common.PtrTo(matchType),
+		Value: common.PtrTo(path.Path),
+	}
+}